@@ -1,12 +1,12 @@
 package selfupdate
 
 import (
+	"context"
 	"fmt"
 	"runtime"
 	"strings"
 
 	"github.com/blang/semver"
-	"github.com/google/go-github/github"
 )
 
 // ReleaseType defines what kind of releases caller wants to upgrade to
@@ -23,81 +23,114 @@ const (
 // IsAllowed returns true if given ReleaseType is enabled in given mask
 func (f ReleaseType) IsAllowed(flag ReleaseType) bool { return f&flag != 0 }
 
-func findAssetFromRelease(rel *github.RepositoryRelease, suffixes []string, targetVersion string, releaseTypes ReleaseType) (*github.ReleaseAsset, semver.Version, bool) {
-	if targetVersion != "" && targetVersion != rel.GetTagName() {
-		log.Println("Skip", rel.GetTagName(), "not matching to specified version", targetVersion)
+func findAssetFromRelease(rel *SourceRelease, targetVersion string, constraint semver.Range, releaseTypes ReleaseType, matcher AssetMatcher, logger Logger) (*SourceAsset, semver.Version, bool) {
+	// An exact tag pin (targetVersion set and not parsed as a range) is
+	// returned even if it's a draft or pre-release, since the caller asked
+	// for that precise tag. A range/constraint query, like an empty
+	// targetVersion, must still honor the draft/pre-release/release-type
+	// filters below.
+	exactPin := targetVersion != "" && constraint == nil
+
+	if targetVersion != "" && constraint == nil && targetVersion != rel.TagName {
+		logger.Debugf("Skip %s: not matching to specified version %s", rel.TagName, targetVersion)
 		return nil, semver.Version{}, false
 	}
 
-	if targetVersion == "" && rel.GetDraft() {
-		log.Println("Skip draft version", rel.GetTagName())
+	if !exactPin && rel.Draft {
+		logger.Debugf("Skip draft version %s", rel.TagName)
 		return nil, semver.Version{}, false
-	} else if targetVersion == "" && rel.GetPrerelease() && !releaseTypes.IsAllowed(PRERELEASE) {
-		log.Println("Skip pre-release version", rel.GetTagName())
+	} else if !exactPin && rel.Prerelease && !releaseTypes.IsAllowed(PRERELEASE) {
+		logger.Debugf("Skip pre-release version %s", rel.TagName)
 		return nil, semver.Version{}, false
 	} else if !releaseTypes.IsAllowed(RELEASE) {
-		log.Println("Skip release version", rel.GetTagName())
+		logger.Debugf("Skip release version %s", rel.TagName)
 		return nil, semver.Version{}, false
 	}
 
-	verText := strings.TrimPrefix(rel.GetTagName(), "v")
+	verText := strings.TrimPrefix(rel.TagName, "v")
 
 	// If semver cannot parse the version text, it means that the text is not adopting
 	// the semantic versioning. So it should be skipped.
 	ver, err := semver.Make(verText)
 	if err != nil {
-		log.Println("Failed to parse a semantic version", verText)
+		logger.Debugf("Failed to parse a semantic version %q: %s", verText, err)
 		return nil, semver.Version{}, false
 	}
 
-	for _, asset := range rel.Assets {
-		name := asset.GetName()
-		for _, s := range suffixes {
-			if strings.HasSuffix(name, s) {
-				return &asset, ver, true
-			}
+	if constraint != nil && !constraint(ver) {
+		logger.Debugf("Skip %s: not satisfying version constraint %s", rel.TagName, targetVersion)
+		return nil, semver.Version{}, false
+	}
+
+	var best *SourceAsset
+	bestScore := 0
+	for i, asset := range rel.Assets {
+		if score, ok := matcher.Match(asset.Name, runtime.GOOS, runtime.GOARCH, logger); ok && (best == nil || score > bestScore) {
+			best = &rel.Assets[i]
+			bestScore = score
 		}
 	}
+	if best == nil {
+		logger.Debugf("No suitable asset was found in release %s", rel.TagName)
+		return nil, semver.Version{}, false
+	}
 
-	log.Println("No suitable asset was found in release", rel.GetTagName())
-	return nil, semver.Version{}, false
+	return best, ver, true
 }
 
-func findValidationAsset(rel *github.RepositoryRelease, validationName string) (*github.ReleaseAsset, bool) {
-	for _, asset := range rel.Assets {
-		if asset.GetName() == validationName {
-			return &asset, true
+func findValidationAsset(rel *SourceRelease, validationName string) (*SourceAsset, bool) {
+	for i, asset := range rel.Assets {
+		if asset.Name == validationName {
+			return &rel.Assets[i], true
 		}
 	}
 	return nil, false
 }
 
-func findReleaseAndAsset(rels []*github.RepositoryRelease, targetVersion string, releaseTypes ReleaseType) (*github.RepositoryRelease, *github.ReleaseAsset, semver.Version, bool) {
-	// Generate candidates
-	suffixes := make([]string, 0, 2*7*2)
-	for _, sep := range []rune{'_', '-'} {
-		for _, ext := range []string{".zip", ".tar.gz", ".gzip", ".gz", ".tar.xz", ".xz", ""} {
-			suffix := fmt.Sprintf("%s%c%s%s", runtime.GOOS, sep, runtime.GOARCH, ext)
-			suffixes = append(suffixes, suffix)
-			if runtime.GOOS == "windows" {
-				suffix = fmt.Sprintf("%s%c%s.exe%s", runtime.GOOS, sep, runtime.GOARCH, ext)
-				suffixes = append(suffixes, suffix)
-			}
+// containsTag reports whether rels contains a release tagged tag, used to
+// short-circuit pagination once a requested version is found. The
+// comparison ignores a leading "v" on either side, since tag may be a bare
+// version (e.g. "1.2.0") that findReleaseAndAsset matches against a "v"-
+// prefixed release tag (e.g. "v1.2.0") via its implicit equality range.
+func containsTag(rels []*SourceRelease, tag string) bool {
+	tag = strings.TrimPrefix(tag, "v")
+	for _, rel := range rels {
+		if strings.TrimPrefix(rel.TagName, "v") == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func findReleaseAndAsset(rels []*SourceRelease, targetVersion string, releaseTypes ReleaseType, matcher AssetMatcher, logger Logger) (*SourceRelease, *SourceAsset, semver.Version, bool) {
+	if matcher == nil {
+		matcher = defaultAssetMatcher{}
+	}
+
+	// targetVersion may be either an exact tag or a semver range/constraint
+	// such as ">=1.2.0 <2.0.0". When it parses as a valid range,
+	// prefer range matching so the highest satisfying version wins;
+	// otherwise fall back to the historical exact-tag comparison. Tilde and
+	// caret shorthands such as "~1.4" are not range syntax for ParseRange,
+	// so they take the exact-tag path.
+	var constraint semver.Range
+	if targetVersion != "" {
+		if r, err := semver.ParseRange(strings.TrimPrefix(targetVersion, "v")); err == nil {
+			constraint = r
 		}
 	}
 
 	var ver semver.Version
-	var asset *github.ReleaseAsset
-	var release *github.RepositoryRelease
+	var asset *SourceAsset
+	var release *SourceRelease
 
 	// Find the latest version from the list of releases.
-	// Returned list from GitHub API is in the order of the date when created.
+	// Returned list from the release source is in the order of the date when created.
 	//   ref: https://github.com/rhysd/go-github-selfupdate/issues/11
 	for _, rel := range rels {
-		if a, v, ok := findAssetFromRelease(rel, suffixes, targetVersion, releaseTypes); ok {
+		if a, v, ok := findAssetFromRelease(rel, targetVersion, constraint, releaseTypes, matcher, logger); ok {
 			// Note: any version with suffix is less than any version without suffix.
 			// e.g. 0.0.1 > 0.0.1-beta
-			fmt.Println(v)
 			if release == nil || v.GTE(ver) {
 				ver = v
 				asset = a
@@ -107,78 +140,125 @@ func findReleaseAndAsset(rels []*github.RepositoryRelease, targetVersion string,
 	}
 
 	if release == nil {
-		log.Println("Could not find any release for", runtime.GOOS, "and", runtime.GOARCH)
+		logger.Infof("Could not find any release for %s and %s", runtime.GOOS, runtime.GOARCH)
 		return nil, nil, semver.Version{}, false
 	}
 
 	return release, asset, ver, true
 }
 
+// listReleases fetches pages of owner/repo's releases from source until it
+// has enough to proceed: it stops as soon as a page contains the exact
+// tag named by version (no point scanning further once the requested
+// release is in hand), once maxReleases releases have been collected (0
+// means unbounded), or once the source reports there's no next page.
+func listReleases(ctx context.Context, source ReleaseSource, owner, repo, version string, perPage, maxReleases int, logger Logger) ([]*SourceRelease, error) {
+	opts := &ListOptions{PerPage: perPage}
+	var rels []*SourceRelease
+	for {
+		page, resp, err := source.ListReleases(ctx, owner, repo, opts)
+		if err != nil {
+			return rels, err
+		}
+		rels = append(rels, page...)
+
+		if version != "" && containsTag(rels, version) {
+			break
+		}
+		if maxReleases > 0 && len(rels) >= maxReleases {
+			break
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return rels, nil
+}
+
 func (up *Updater) detectVersion(slug string, version string, releaseTypes ReleaseType) (release *Release, found bool, err error) {
+	logger := up.logger
+	if logger == nil {
+		logger = noopLogger{}
+	}
 
 	repo := strings.Split(slug, "/")
 	if len(repo) != 2 || repo[0] == "" || repo[1] == "" {
 		return nil, false, fmt.Errorf("Invalid slug format. It should be 'owner/name': %s", slug)
 	}
 
-	rels, res, err := up.api.Repositories.ListReleases(up.apiCtx, repo[0], repo[1], nil)
+	rels, err := listReleases(up.apiCtx, up.source, repo[0], repo[1], version, up.perPage, up.maxReleases, logger)
 	if err != nil {
-		log.Println("API returned an error response:", err)
-		if res != nil && res.StatusCode == 404 {
-			// 404 means repository not found or release not found. It's not an error here.
-			err = nil
-			log.Println("API returned 404. Repository or release not found")
+		logger.Warnf("Release source returned an error response: %s", err)
+		if err == ErrNotFound {
+			// Not found means repository or release not found. It's not an error here.
+			return nil, false, nil
 		}
 		return nil, false, err
 	}
 
-	rel, asset, ver, found := findReleaseAndAsset(rels, version, releaseTypes)
+	rel, asset, ver, found := findReleaseAndAsset(rels, version, releaseTypes, up.matcher, logger)
 	if !found {
 		return nil, false, nil
 	}
 
-	url := asset.GetBrowserDownloadURL()
-	log.Println("Successfully fetched the latest release. tag:", rel.GetTagName(), ", name:", rel.GetName(), ", URL:", rel.GetURL(), ", Asset:", url)
+	url := asset.BrowserDownloadURL
+	logger.Infof("Successfully fetched the latest release. tag: %s, name: %s, URL: %s, Asset: %s", rel.TagName, rel.Name, rel.URL, url)
 
-	publishedAt := rel.GetPublishedAt().Time
+	publishedAt := rel.PublishedAt
 	release = &Release{
-		ver,
-		url,
-		asset.GetSize(),
-		asset.GetID(),
-		-1,
-		rel.GetHTMLURL(),
-		rel.GetBody(),
-		rel.GetName(),
-		&publishedAt,
-		repo[0],
-		repo[1],
+		Version:           ver,
+		AssetURL:          url,
+		AssetByteSize:     asset.Size,
+		AssetID:           asset.ID,
+		ValidationAssetID: -1,
+		URL:               rel.URL,
+		ReleaseNotes:      rel.Body,
+		Name:              rel.Name,
+		PublishedAt:       &publishedAt,
+		RepoOwner:         repo[0],
+		RepoName:          repo[1],
+		AssetName:         asset.Name,
 	}
 
 	if up.validator != nil {
-		validationName := asset.GetName() + up.validator.Suffix()
+		if mv, ok := up.validator.(ManifestValidator); ok {
+			if manifestAsset, ok := findValidationAsset(rel, mv.ManifestName()); ok {
+				release.ValidationAssetID = manifestAsset.ID
+				release.validationIsManifest = true
+				return release, true, nil
+			}
+			logger.Debugf("No combined manifest %s in release %s - falling back to per-asset validation file", mv.ManifestName(), rel.TagName)
+		}
+
+		validationName := asset.Name + up.validator.Suffix()
 		validationAsset, ok := findValidationAsset(rel, validationName)
 		if !ok {
 			return nil, false, fmt.Errorf("Failed finding validation file %q", validationName)
 		}
-		release.ValidationAssetID = validationAsset.GetID()
+		release.ValidationAssetID = validationAsset.ID
 	}
 
 	return release, true, nil
 }
 
-// DetectLatest tries to get the latest version of the repository on GitHub. 'slug' means 'owner/name' formatted string.
-// It fetches releases information from GitHub API and find out the latest release with matching the tag names and asset names.
-// Drafts and pre-releases are ignored. Assets would be suffixed by the OS name and the arch name such as 'foo_linux_amd64'
-// where 'foo' is a command name. '-' can also be used as a separator. File can be compressed with zip, gzip, zxip, tar&zip or tar&zxip.
-// So the asset can have a file extension for the corresponding compression format such as '.zip'.
-// On Windows, '.exe' also can be contained such as 'foo_windows_amd64.exe.zip'.
+// DetectLatest tries to get the latest version of the repository. 'slug' means 'owner/name' formatted string.
+// It fetches releases information from the Updater's ReleaseSource (GitHub by default) and finds out the latest
+// release with matching the tag names and asset names.
+// Drafts and pre-releases are ignored. By default, assets are matched by an OS name and arch name suffix such as
+// 'foo_linux_amd64' where 'foo' is a command name. '-' can also be used as a separator. File can be compressed with
+// zip, gzip, zxip, tar&zip or tar&zxip. So the asset can have a file extension for the corresponding compression
+// format such as '.zip'. On Windows, '.exe' also can be contained such as 'foo_windows_amd64.exe.zip'.
+// Set Updater's AssetMatcher (TemplateMatcher or RegexpMatcher) to match a different naming scheme.
 func (up *Updater) DetectLatest(slug string) (release *Release, found bool, err error) {
 	return up.DetectVersion(slug, "")
 }
 
 // DetectVersion tries to get the given version of the repository on Github. `slug` means `owner/name` formatted string.
-// And version indicates the required version.
+// version indicates the required version. It may be an exact tag such as "v1.2.0", or a semver range/constraint
+// such as ">=1.2.0 <2.0.0", in which case the highest release satisfying the constraint is selected.
+// Constraints use blang/semver's range syntax; tilde and caret shorthands such as "~1.4" are not parsed as
+// ranges and are compared against tags as an exact string.
 func (up *Updater) DetectVersion(slug string, version string) (release *Release, found bool, err error) {
 	return up.detectVersion(slug, version, RELEASE)
 }
@@ -191,7 +271,8 @@ func (up *Updater) DetectLatestOfType(slug string, releaseTypes ReleaseType) (re
 }
 
 // DetectVersionOfType tries to get the given version of the repository on Github. `slug` means `owner/name` formatted string.
-// And version indicates the required version. ReleaseType defines allowed release types, such as RELEASE, PRERELEASE or DRAFT.
+// version indicates the required version, either an exact tag or a semver range/constraint (see DetectVersion).
+// ReleaseType defines allowed release types, RELEASE and PRERELEASE.
 // These can be combined like bit masks: RELEASE | PRERELEASE or PRERELEASE | DRAFT
 func (up *Updater) DetectVersionOfType(slug string, version string, releaseTypes ReleaseType) (release *Release, found bool, err error) {
 	return up.detectVersion(slug, version, releaseTypes)
@@ -209,7 +290,8 @@ func DetectLatestOfType(slug string, version string, releaseTypes ReleaseType) (
 	return DefaultUpdater().DetectLatestOfType("", releaseTypes)
 }
 
-// DetectVersion detects the given release of the slug (owner/repo) from its version.
+// DetectVersion detects the given release of the slug (owner/repo) from its version, which may be an
+// exact tag or a semver range/constraint (see Updater.DetectVersion).
 func DetectVersion(slug string, version string) (*Release, bool, error) {
 	return DefaultUpdater().DetectVersion(slug, version)
 }
@@ -217,6 +299,5 @@ func DetectVersion(slug string, version string) (*Release, bool, error) {
 // DetectVersionOfType detects the given release of the slug (owner/repo) with given release types (RELEASE, PRERELEASE).
 // This function is a shortcut version of updater.DetectVersionOfType() method.
 func DetectVersionOfType(slug string, version string, releaseTypes ReleaseType) (*Release, bool, error) {
-	fmt.Println("Pling")
 	return DefaultUpdater().DetectVersionOfType(slug, version, releaseTypes)
 }
@@ -0,0 +1,99 @@
+package selfupdate
+
+import "testing"
+
+// alwaysMatchMatcher matches every asset with a constant score, so tests
+// can focus on release/tag selection rather than asset matching.
+type alwaysMatchMatcher struct{}
+
+func (alwaysMatchMatcher) Match(assetName, goos, goarch string, logger Logger) (int, bool) {
+	return 1, true
+}
+
+func testRelease(tag string) *SourceRelease {
+	return &SourceRelease{
+		TagName: tag,
+		Assets:  []SourceAsset{{ID: 1, Name: tag + "_asset"}},
+	}
+}
+
+func TestFindReleaseAndAssetExactTag(t *testing.T) {
+	rels := []*SourceRelease{testRelease("v1.2.0"), testRelease("v1.3.0")}
+
+	rel, _, ver, found := findReleaseAndAsset(rels, "v1.2.0", RELEASE, alwaysMatchMatcher{}, noopLogger{})
+	if !found {
+		t.Fatal("expected to find a release")
+	}
+	if rel.TagName != "v1.2.0" {
+		t.Errorf("expected tag v1.2.0, got %s", rel.TagName)
+	}
+	if ver.String() != "1.2.0" {
+		t.Errorf("expected version 1.2.0, got %s", ver.String())
+	}
+}
+
+func TestFindReleaseAndAssetSemverRange(t *testing.T) {
+	rels := []*SourceRelease{testRelease("v1.2.0"), testRelease("v1.3.0"), testRelease("v2.0.0")}
+
+	rel, _, _, found := findReleaseAndAsset(rels, ">=1.2.0 <2.0.0", RELEASE, alwaysMatchMatcher{}, noopLogger{})
+	if !found {
+		t.Fatal("expected to find a release")
+	}
+	if rel.TagName != "v1.3.0" {
+		t.Errorf("expected the highest release satisfying the range (v1.3.0), got %s", rel.TagName)
+	}
+}
+
+// TestFindReleaseAndAssetBareVersionIsEqualityConstraint documents that a
+// bare version such as "1.2.0" parses as a valid semver.Range (an implicit
+// "==1.2.0" constraint), so it is matched via constraint satisfaction
+// rather than exact tag string comparison. This is intentional: it lets
+// "v1.2.0" and "1.2.0" both select the same release.
+func TestFindReleaseAndAssetBareVersionIsEqualityConstraint(t *testing.T) {
+	rels := []*SourceRelease{testRelease("v1.2.0")}
+
+	rel, _, _, found := findReleaseAndAsset(rels, "1.2.0", RELEASE, alwaysMatchMatcher{}, noopLogger{})
+	if !found {
+		t.Fatal("expected bare version 1.2.0 to match tag v1.2.0 via equality constraint")
+	}
+	if rel.TagName != "v1.2.0" {
+		t.Errorf("expected tag v1.2.0, got %s", rel.TagName)
+	}
+}
+
+// TestFindReleaseAndAssetRangeSkipsDraft guards against a regression where
+// a range/constraint query (as opposed to an exact tag pin) bypassed the
+// draft and pre-release filters, since those were gated on targetVersion
+// being non-empty rather than on whether targetVersion was an exact pin.
+func TestFindReleaseAndAssetRangeSkipsDraft(t *testing.T) {
+	rel := testRelease("v1.5.0")
+	rel.Draft = true
+	rels := []*SourceRelease{rel}
+
+	_, _, _, found := findReleaseAndAsset(rels, ">=1.0.0 <2.0.0", RELEASE, alwaysMatchMatcher{}, noopLogger{})
+	if found {
+		t.Fatal("expected a draft release to be skipped for a range query")
+	}
+}
+
+// TestFindReleaseAndAssetRangeSkipsPrerelease mirrors the draft case above
+// for a pre-release release under a range query with only RELEASE allowed.
+func TestFindReleaseAndAssetRangeSkipsPrerelease(t *testing.T) {
+	rel := testRelease("v1.5.0-beta")
+	rel.Prerelease = true
+	rels := []*SourceRelease{rel}
+
+	_, _, _, found := findReleaseAndAsset(rels, ">=1.0.0 <2.0.0", RELEASE, alwaysMatchMatcher{}, noopLogger{})
+	if found {
+		t.Fatal("expected a pre-release to be skipped for a range query when only RELEASE is allowed")
+	}
+}
+
+func TestFindReleaseAndAssetNoMatch(t *testing.T) {
+	rels := []*SourceRelease{testRelease("v1.2.0")}
+
+	_, _, _, found := findReleaseAndAsset(rels, "v9.9.9", RELEASE, alwaysMatchMatcher{}, noopLogger{})
+	if found {
+		t.Fatal("expected no release to match an unrelated exact tag")
+	}
+}
@@ -0,0 +1,17 @@
+package selfupdate
+
+// Logger receives diagnostic output from Updater. Set it via an Updater
+// option; the zero value of Updater falls back to a no-op logger, so
+// embedding selfupdate in a CLI never writes to stdout unless the caller
+// explicitly wires up a Logger.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Debugf(string, ...interface{}) {}
+func (noopLogger) Infof(string, ...interface{})  {}
+func (noopLogger) Warnf(string, ...interface{})  {}
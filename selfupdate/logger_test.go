@@ -0,0 +1,13 @@
+package selfupdate
+
+import "testing"
+
+// TestNoopLoggerDiscardsOutput confirms noopLogger implements Logger and
+// that none of its methods panic when called with format args, since it's
+// the fallback Updater uses whenever no Logger is configured.
+func TestNoopLoggerDiscardsOutput(t *testing.T) {
+	var logger Logger = noopLogger{}
+	logger.Debugf("debug %s", "x")
+	logger.Infof("info %s", "x")
+	logger.Warnf("warn %s", "x")
+}
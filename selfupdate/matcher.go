@@ -0,0 +1,135 @@
+package selfupdate
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// AssetMatcher decides whether a release asset is a suitable download for
+// the running OS/architecture, and how confidently. Updater's default
+// matcher reproduces the historical "GOOS_GOARCH[.ext]" suffix matching;
+// a TemplateMatcher or RegexpMatcher can be set on Updater instead to
+// support other naming schemes (e.g. GoReleaser's "myapp_Linux_x86_64.zip")
+// without requiring the upstream project to rename its assets.
+type AssetMatcher interface {
+	// Match reports whether assetName is a suitable download for goos/goarch,
+	// and a score used to rank multiple matching assets in the same release.
+	// Higher scores win; ok is false when the asset does not match at all.
+	// logger receives diagnostics such as a malformed Template/Pattern; it is
+	// never nil.
+	Match(assetName, goos, goarch string, logger Logger) (score int, ok bool)
+}
+
+// defaultAssetMatcher reproduces Updater's original asset matching: a
+// fixed cartesian product of separators and archive extensions appended
+// to "GOOS_GOARCH" (or "GOOS_GOARCH.exe" on Windows).
+type defaultAssetMatcher struct{}
+
+func (defaultAssetMatcher) Match(assetName, goos, goarch string, logger Logger) (int, bool) {
+	for _, sep := range []rune{'_', '-'} {
+		for _, ext := range []string{".zip", ".tar.gz", ".gzip", ".gz", ".tar.xz", ".xz", ""} {
+			if strings.HasSuffix(assetName, fmt.Sprintf("%s%c%s%s", goos, sep, goarch, ext)) {
+				return 1, true
+			}
+			if goos == "windows" && strings.HasSuffix(assetName, fmt.Sprintf("%s%c%s.exe%s", goos, sep, goarch, ext)) {
+				return 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// goreleaserArchAliases maps Go's GOARCH names to the aliases commonly
+// used by GoReleaser-style release naming.
+var goreleaserArchAliases = map[string]string{
+	"amd64": "x86_64",
+	"386":   "i386",
+	"arm64": "aarch64",
+}
+
+func goreleaserOS(goos string) string {
+	switch goos {
+	case "darwin":
+		return "Darwin"
+	case "linux":
+		return "Linux"
+	case "windows":
+		return "Windows"
+	default:
+		return strings.Title(goos)
+	}
+}
+
+func goreleaserArch(goarch string) string {
+	if alias, ok := goreleaserArchAliases[goarch]; ok {
+		return alias
+	}
+	return goarch
+}
+
+// templateMatchData is the data made available to a TemplateMatcher's template.
+type templateMatchData struct {
+	Name string
+	OS   string
+	Arch string
+}
+
+// TemplateMatcher matches asset names rendered from a user-provided Go
+// template, for projects whose asset naming doesn't fit the
+// "GOOS_GOARCH[.ext]" convention. OS and Arch are rendered using the
+// capitalized/aliased forms GoReleaser uses (e.g. "Linux", "x86_64"),
+// since that is the most common source of non-conforming names.
+//
+//	m := &TemplateMatcher{Name: "myapp", Template: "{{.Name}}_{{.OS}}_{{.Arch}}.tar.gz"}
+//	// matches "myapp_Linux_x86_64.tar.gz" on linux/amd64
+type TemplateMatcher struct {
+	Name     string
+	Template string
+}
+
+// Match implements AssetMatcher.
+func (m *TemplateMatcher) Match(assetName, goos, goarch string, logger Logger) (int, bool) {
+	tmpl, err := template.New("asset").Parse(m.Template)
+	if err != nil {
+		logger.Warnf("Failed to parse asset name template %q: %s", m.Template, err)
+		return 0, false
+	}
+
+	var buf bytes.Buffer
+	data := templateMatchData{Name: m.Name, OS: goreleaserOS(goos), Arch: goreleaserArch(goarch)}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		logger.Warnf("Failed to render asset name template %q: %s", m.Template, err)
+		return 0, false
+	}
+
+	if buf.String() == assetName {
+		return 1, true
+	}
+	return 0, false
+}
+
+// RegexpMatcher matches asset names against a user-provided regular
+// expression. The literals "$GOOS" and "$GOARCH" in Pattern are expanded
+// to the running OS and architecture before compiling, e.g.
+//
+//	m := &RegexpMatcher{Pattern: `myapp-v[0-9.]+-$GOOS-$GOARCH\.tar\.gz`}
+type RegexpMatcher struct {
+	Pattern string
+}
+
+// Match implements AssetMatcher.
+func (m *RegexpMatcher) Match(assetName, goos, goarch string, logger Logger) (int, bool) {
+	pattern := strings.NewReplacer("$GOOS", goos, "$GOARCH", goarch).Replace(m.Pattern)
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		logger.Warnf("Failed to compile asset regexp %q: %s", pattern, err)
+		return 0, false
+	}
+	if re.MatchString(assetName) {
+		return 1, true
+	}
+	return 0, false
+}
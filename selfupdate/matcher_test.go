@@ -0,0 +1,96 @@
+package selfupdate
+
+import "testing"
+
+// capturingLogger records the messages passed to Warnf so tests can assert
+// a diagnostic was actually logged, not just that Match returned ok=false.
+type capturingLogger struct {
+	warnings []string
+}
+
+func (l *capturingLogger) Debugf(format string, args ...interface{}) {}
+func (l *capturingLogger) Infof(format string, args ...interface{})  {}
+func (l *capturingLogger) Warnf(format string, args ...interface{}) {
+	l.warnings = append(l.warnings, format)
+}
+
+func TestDefaultAssetMatcher(t *testing.T) {
+	m := defaultAssetMatcher{}
+
+	if _, ok := m.Match("myapp_linux_amd64.tar.gz", "linux", "amd64", noopLogger{}); !ok {
+		t.Error("expected a GOOS_GOARCH suffix match")
+	}
+	if _, ok := m.Match("myapp-windows-amd64.exe.zip", "windows", "amd64", noopLogger{}); !ok {
+		t.Error("expected a Windows .exe suffix match")
+	}
+	if _, ok := m.Match("myapp_darwin_amd64.tar.gz", "linux", "amd64", noopLogger{}); ok {
+		t.Error("expected no match for a mismatched OS")
+	}
+}
+
+func TestTemplateMatcherGoReleaserStyle(t *testing.T) {
+	m := &TemplateMatcher{Name: "myapp", Template: "{{.Name}}_{{.OS}}_{{.Arch}}.tar.gz"}
+
+	if _, ok := m.Match("myapp_Linux_x86_64.tar.gz", "linux", "amd64", noopLogger{}); !ok {
+		t.Error("expected a GoReleaser-style Linux/x86_64 match")
+	}
+	if _, ok := m.Match("myapp_Darwin_aarch64.tar.gz", "darwin", "arm64", noopLogger{}); !ok {
+		t.Error("expected a GoReleaser-style Darwin/aarch64 match")
+	}
+	if _, ok := m.Match("myapp_linux_amd64.tar.gz", "linux", "amd64", noopLogger{}); ok {
+		t.Error("expected no match for the un-aliased/un-capitalized asset name")
+	}
+}
+
+func TestTemplateMatcherMalformedTemplate(t *testing.T) {
+	m := &TemplateMatcher{Name: "myapp", Template: "{{.Name"}
+	logger := &capturingLogger{}
+
+	if _, ok := m.Match("myapp_Linux_x86_64.tar.gz", "linux", "amd64", logger); ok {
+		t.Fatal("expected a malformed template to never match")
+	}
+	if len(logger.warnings) != 1 {
+		t.Errorf("expected exactly one warning to be logged, got %d", len(logger.warnings))
+	}
+}
+
+func TestRegexpMatcher(t *testing.T) {
+	m := &RegexpMatcher{Pattern: `myapp-v[0-9.]+-$GOOS-$GOARCH\.tar\.gz`}
+
+	if _, ok := m.Match("myapp-v1.2.3-linux-amd64.tar.gz", "linux", "amd64", noopLogger{}); !ok {
+		t.Error("expected the pattern to match after $GOOS/$GOARCH expansion")
+	}
+	if _, ok := m.Match("myapp-v1.2.3-darwin-amd64.tar.gz", "linux", "amd64", noopLogger{}); ok {
+		t.Error("expected no match for a mismatched OS")
+	}
+}
+
+func TestRegexpMatcherMalformedPattern(t *testing.T) {
+	m := &RegexpMatcher{Pattern: `myapp-v[0-9.+-$GOOS-$GOARCH\.tar\.gz`}
+	logger := &capturingLogger{}
+
+	if _, ok := m.Match("myapp-v1.2.3-linux-amd64.tar.gz", "linux", "amd64", logger); ok {
+		t.Fatal("expected a malformed regexp to never match")
+	}
+	if len(logger.warnings) != 1 {
+		t.Errorf("expected exactly one warning to be logged, got %d", len(logger.warnings))
+	}
+}
+
+func TestGoreleaserArch(t *testing.T) {
+	cases := map[string]string{"amd64": "x86_64", "386": "i386", "arm64": "aarch64", "mips64": "mips64"}
+	for goarch, want := range cases {
+		if got := goreleaserArch(goarch); got != want {
+			t.Errorf("goreleaserArch(%q) = %q, want %q", goarch, got, want)
+		}
+	}
+}
+
+func TestGoreleaserOS(t *testing.T) {
+	cases := map[string]string{"linux": "Linux", "darwin": "Darwin", "windows": "Windows", "freebsd": "Freebsd"}
+	for goos, want := range cases {
+		if got := goreleaserOS(goos); got != want {
+			t.Errorf("goreleaserOS(%q) = %q, want %q", goos, got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package selfupdate
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+// fakeReleaseSource serves pages from a fixed slice of releases, one page
+// per ListReleases call, so tests can assert exactly how many pages
+// listReleases fetched before stopping.
+type fakeReleaseSource struct {
+	pages   [][]*SourceRelease
+	fetched int
+}
+
+func (s *fakeReleaseSource) ListReleases(ctx context.Context, owner, repo string, opts *ListOptions) ([]*SourceRelease, *ListResponse, error) {
+	page := opts.Page
+	if page == 0 {
+		page = 1
+	}
+	s.fetched++
+
+	rels := s.pages[page-1]
+	next := 0
+	if page < len(s.pages) {
+		next = page + 1
+	}
+	return rels, &ListResponse{NextPage: next}, nil
+}
+
+func (s *fakeReleaseSource) DownloadAsset(ctx context.Context, assetID int64) (io.ReadCloser, error) {
+	return nil, nil
+}
+
+func TestListReleasesStopsAtExactTag(t *testing.T) {
+	source := &fakeReleaseSource{pages: [][]*SourceRelease{
+		{testRelease("v2.0.0")},
+		{testRelease("v1.5.0")},
+		{testRelease("v1.0.0")},
+	}}
+
+	rels, err := listReleases(context.Background(), source, "o", "r", "v1.5.0", 0, 0, noopLogger{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if source.fetched != 2 {
+		t.Errorf("expected pagination to stop once the exact tag was found (2 pages), fetched %d", source.fetched)
+	}
+	if len(rels) != 2 {
+		t.Errorf("expected 2 releases collected, got %d", len(rels))
+	}
+}
+
+func TestListReleasesStopsAtExactTagIgnoringVPrefix(t *testing.T) {
+	source := &fakeReleaseSource{pages: [][]*SourceRelease{
+		{testRelease("v2.0.0")},
+		{testRelease("v1.5.0")},
+		{testRelease("v1.0.0")},
+	}}
+
+	rels, err := listReleases(context.Background(), source, "o", "r", "1.5.0", 0, 0, noopLogger{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if source.fetched != 2 {
+		t.Errorf("expected a bare version to short-circuit pagination like its \"v\"-prefixed tag (2 pages), fetched %d", source.fetched)
+	}
+	if len(rels) != 2 {
+		t.Errorf("expected 2 releases collected, got %d", len(rels))
+	}
+}
+
+func TestListReleasesStopsAtMaxReleases(t *testing.T) {
+	source := &fakeReleaseSource{pages: [][]*SourceRelease{
+		{testRelease("v3.0.0")},
+		{testRelease("v2.0.0")},
+		{testRelease("v1.0.0")},
+	}}
+
+	rels, err := listReleases(context.Background(), source, "o", "r", "", 0, 2, noopLogger{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if source.fetched != 2 {
+		t.Errorf("expected pagination to stop once maxReleases was reached (2 pages), fetched %d", source.fetched)
+	}
+	if len(rels) != 2 {
+		t.Errorf("expected 2 releases collected, got %d", len(rels))
+	}
+}
+
+func TestListReleasesExhaustsAllPages(t *testing.T) {
+	source := &fakeReleaseSource{pages: [][]*SourceRelease{
+		{testRelease("v3.0.0")},
+		{testRelease("v2.0.0")},
+		{testRelease("v1.0.0")},
+	}}
+
+	rels, err := listReleases(context.Background(), source, "o", "r", "", 0, 0, noopLogger{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if source.fetched != 3 {
+		t.Errorf("expected pagination to exhaust all 3 pages when unbounded, fetched %d", source.fetched)
+	}
+	if len(rels) != 3 {
+		t.Errorf("expected 3 releases collected, got %d", len(rels))
+	}
+}
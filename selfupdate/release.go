@@ -0,0 +1,56 @@
+package selfupdate
+
+import (
+	"time"
+
+	"github.com/blang/semver"
+)
+
+// Release represents a release asset for updating the application.
+type Release struct {
+	// Version is the version of the release.
+	Version semver.Version
+	// AssetURL is the URL to the uploaded file for the release.
+	AssetURL string
+	// AssetByteSize is the size of the asset in bytes.
+	AssetByteSize int
+	// AssetID is the ID of the asset on the release source.
+	AssetID int64
+	// ValidationAssetID is the ID of the asset used to validate this release's
+	// AssetID asset, or -1 when no Validator is configured.
+	ValidationAssetID int64
+	// URL is the webpage URL of the release itself, not the asset.
+	URL string
+	// ReleaseNotes is the content of the release notes for the release.
+	ReleaseNotes string
+	// Name is the name of the release.
+	Name string
+	// PublishedAt is the time the release was published at.
+	PublishedAt *time.Time
+	// RepoOwner is the owner of the repository of the release.
+	RepoOwner string
+	// RepoName is the name of the repository of the release.
+	RepoName string
+
+	// AssetName is the name of the AssetID asset as reported by the
+	// release source, e.g. "myapp_linux_amd64.tar.gz". It is needed to
+	// look up this asset's entry in a combined validation manifest.
+	AssetName string
+
+	// validationIsManifest is true when ValidationAssetID refers to a
+	// combined manifest shared by every asset in the release (e.g.
+	// checksums.txt) rather than a sibling file dedicated to AssetID.
+	validationIsManifest bool
+}
+
+// Validate checks data (the downloaded AssetID asset) against validationData (the
+// downloaded ValidationAssetID asset) using validator. When validator implements
+// ManifestValidator and ValidationAssetID was resolved from a combined manifest,
+// ValidateFromManifest is used with AssetName so the right entry is looked up;
+// otherwise the per-asset Validate(data, sig) path is used.
+func (release *Release) Validate(validator Validator, data []byte, validationData []byte) error {
+	if mv, ok := validator.(ManifestValidator); ok && release.validationIsManifest {
+		return mv.ValidateFromManifest(release.AssetName, data, validationData)
+	}
+	return validator.Validate(data, validationData)
+}
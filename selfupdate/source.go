@@ -0,0 +1,393 @@
+package selfupdate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+// ErrNotFound is returned by a ReleaseSource's ListReleases when the
+// repository or its releases could not be found. Updater treats this the
+// same way it has always treated a 404 from the GitHub API: not an error
+// worth surfacing to the caller, just "nothing to update to".
+var ErrNotFound = errors.New("selfupdate: repository or release not found")
+
+// SourceRelease is a provider-agnostic view of a single release. Every
+// ReleaseSource implementation is responsible for translating whatever
+// its forge returns into this shape so the rest of the package never has
+// to know which forge a repository is hosted on.
+type SourceRelease struct {
+	TagName     string
+	Name        string
+	Body        string
+	URL         string
+	Draft       bool
+	Prerelease  bool
+	PublishedAt time.Time
+	Assets      []SourceAsset
+}
+
+// SourceAsset is a provider-agnostic view of a single release asset.
+type SourceAsset struct {
+	ID                 int64
+	Name               string
+	Size               int
+	BrowserDownloadURL string
+}
+
+// ListOptions controls pagination of ListReleases.
+type ListOptions struct {
+	// Page is the page to fetch, starting at 1. Zero means the first page.
+	Page int
+	// PerPage is the number of releases to fetch per page. Zero means the
+	// ReleaseSource's default.
+	PerPage int
+}
+
+// ListResponse carries pagination metadata alongside a page of releases.
+type ListResponse struct {
+	// NextPage is the page number of the next page, or 0 if this was the
+	// last page.
+	NextPage int
+}
+
+// ReleaseSource abstracts over the forge hosting a repository's releases.
+// Implementations exist for GitHub, GitLab and Gitea so that Updater can
+// be pointed at a self-hosted instance of any of them without changing
+// any of the `DetectLatest`/`DetectVersion`/`DetectLatestOfType` call
+// sites.
+type ReleaseSource interface {
+	// ListReleases returns one page of the releases of owner/repo, most
+	// recent first.
+	ListReleases(ctx context.Context, owner, repo string, opts *ListOptions) ([]*SourceRelease, *ListResponse, error)
+
+	// DownloadAsset opens the contents of the asset previously returned
+	// in a SourceRelease.Assets slice by this same ReleaseSource.
+	DownloadAsset(ctx context.Context, assetID int64) (io.ReadCloser, error)
+}
+
+// GitHubSource is the default ReleaseSource, backed by the GitHub API.
+// It preserves the behavior Updater has always had.
+type GitHubSource struct {
+	Client *github.Client
+
+	owner, repo string
+}
+
+// NewGitHubSource creates a GitHubSource which lists and downloads
+// releases through client.
+func NewGitHubSource(client *github.Client) *GitHubSource {
+	return &GitHubSource{Client: client}
+}
+
+// ListReleases implements ReleaseSource.
+func (s *GitHubSource) ListReleases(ctx context.Context, owner, repo string, opts *ListOptions) ([]*SourceRelease, *ListResponse, error) {
+	s.owner, s.repo = owner, repo
+
+	ghOpts := &github.ListOptions{}
+	if opts != nil {
+		ghOpts.Page = opts.Page
+		ghOpts.PerPage = opts.PerPage
+	}
+
+	rels, resp, err := s.Client.Repositories.ListReleases(ctx, owner, repo, ghOpts)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+
+	out := make([]*SourceRelease, 0, len(rels))
+	for _, rel := range rels {
+		assets := make([]SourceAsset, 0, len(rel.Assets))
+		for _, a := range rel.Assets {
+			assets = append(assets, SourceAsset{
+				ID:                 a.GetID(),
+				Name:               a.GetName(),
+				Size:               a.GetSize(),
+				BrowserDownloadURL: a.GetBrowserDownloadURL(),
+			})
+		}
+		out = append(out, &SourceRelease{
+			TagName:     rel.GetTagName(),
+			Name:        rel.GetName(),
+			Body:        rel.GetBody(),
+			URL:         rel.GetHTMLURL(),
+			Draft:       rel.GetDraft(),
+			Prerelease:  rel.GetPrerelease(),
+			PublishedAt: rel.GetPublishedAt().Time,
+			Assets:      assets,
+		})
+	}
+	return out, &ListResponse{NextPage: resp.NextPage}, nil
+}
+
+// DownloadAsset implements ReleaseSource.
+func (s *GitHubSource) DownloadAsset(ctx context.Context, assetID int64) (io.ReadCloser, error) {
+	rc, _, err := s.Client.Repositories.DownloadReleaseAsset(ctx, s.owner, s.repo, assetID)
+	return rc, err
+}
+
+// GitLabSource is a ReleaseSource backed by the GitLab Releases API,
+// allowing Updater to point at a self-hosted GitLab instance.
+type GitLabSource struct {
+	// BaseURL is the root of the GitLab instance, e.g.
+	// "https://gitlab.example.com". Defaults to "https://gitlab.com".
+	BaseURL string
+	// Token is sent as a PRIVATE-TOKEN header when set.
+	Token string
+
+	httpClient *http.Client
+	assetURLs  map[int64]string
+	nextID     int64
+}
+
+// NewGitLabSource creates a GitLabSource talking to baseURL. An empty
+// baseURL defaults to the public gitlab.com instance.
+func NewGitLabSource(baseURL, token string) *GitLabSource {
+	if baseURL == "" {
+		baseURL = "https://gitlab.com"
+	}
+	return &GitLabSource{BaseURL: baseURL, Token: token, httpClient: http.DefaultClient, assetURLs: map[int64]string{}}
+}
+
+type gitlabRelease struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ReleasedAt  time.Time `json:"released_at"`
+	Upcoming    bool      `json:"upcoming_release"`
+	Assets      struct {
+		Links []struct {
+			Name           string `json:"name"`
+			DirectAssetURL string `json:"direct_asset_url"`
+			URL            string `json:"url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+// ListReleases implements ReleaseSource.
+func (s *GitLabSource) ListReleases(ctx context.Context, owner, repo string, opts *ListOptions) ([]*SourceRelease, *ListResponse, error) {
+	project := url.PathEscape(owner + "/" + repo)
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/releases?%s", s.BaseURL, project, pageQuery(opts))
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	if s.Token != "" {
+		req.Header.Set("PRIVATE-TOKEN", s.Token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("gitlab: unexpected status %s for %s", resp.Status, endpoint)
+	}
+
+	var rels []gitlabRelease
+	if err := json.NewDecoder(resp.Body).Decode(&rels); err != nil {
+		return nil, nil, err
+	}
+
+	out := make([]*SourceRelease, 0, len(rels))
+	for _, rel := range rels {
+		assets := make([]SourceAsset, 0, len(rel.Assets.Links))
+		for _, link := range rel.Assets.Links {
+			assetURL := link.DirectAssetURL
+			if assetURL == "" {
+				assetURL = link.URL
+			}
+			id := s.nextID
+			s.nextID++
+			s.assetURLs[id] = assetURL
+			assets = append(assets, SourceAsset{ID: id, Name: link.Name, BrowserDownloadURL: assetURL})
+		}
+		out = append(out, &SourceRelease{
+			TagName:     rel.TagName,
+			Name:        rel.Name,
+			Body:        rel.Description,
+			URL:         fmt.Sprintf("%s/%s/%s/-/releases/%s", s.BaseURL, owner, repo, rel.TagName),
+			Prerelease:  rel.Upcoming,
+			PublishedAt: rel.ReleasedAt,
+			Assets:      assets,
+		})
+	}
+
+	nextPage := 0
+	if n, err := strconv.Atoi(resp.Header.Get("X-Next-Page")); err == nil {
+		nextPage = n
+	}
+	return out, &ListResponse{NextPage: nextPage}, nil
+}
+
+// DownloadAsset implements ReleaseSource.
+func (s *GitLabSource) DownloadAsset(ctx context.Context, assetID int64) (io.ReadCloser, error) {
+	assetURL, ok := s.assetURLs[assetID]
+	if !ok {
+		return nil, fmt.Errorf("gitlab: unknown asset id %d", assetID)
+	}
+	return httpDownload(ctx, s.httpClient, assetURL, s.Token, "PRIVATE-TOKEN")
+}
+
+// GiteaSource is a ReleaseSource backed by the Gitea Releases API, which
+// mirrors the GitHub API closely enough to reuse its JSON shape directly.
+type GiteaSource struct {
+	// BaseURL is the root of the Gitea instance, e.g.
+	// "https://gitea.example.com".
+	BaseURL string
+	// Token is sent as an Authorization: token ... header when set.
+	Token string
+
+	httpClient *http.Client
+	assetURLs  map[int64]string
+}
+
+// NewGiteaSource creates a GiteaSource talking to baseURL.
+func NewGiteaSource(baseURL, token string) *GiteaSource {
+	return &GiteaSource{BaseURL: baseURL, Token: token, httpClient: http.DefaultClient, assetURLs: map[int64]string{}}
+}
+
+type giteaAsset struct {
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	Size               int    `json:"size"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+type giteaRelease struct {
+	TagName    string       `json:"tag_name"`
+	Name       string       `json:"name"`
+	Body       string       `json:"body"`
+	URL        string       `json:"html_url"`
+	Draft      bool         `json:"draft"`
+	Prerelease bool         `json:"prerelease"`
+	CreatedAt  time.Time    `json:"created_at"`
+	Assets     []giteaAsset `json:"assets"`
+}
+
+// ListReleases implements ReleaseSource.
+func (s *GiteaSource) ListReleases(ctx context.Context, owner, repo string, opts *ListOptions) ([]*SourceRelease, *ListResponse, error) {
+	endpoint := fmt.Sprintf("%s/api/v1/repos/%s/%s/releases?%s", s.BaseURL, owner, repo, pageQuery(opts))
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req = req.WithContext(ctx)
+	if s.Token != "" {
+		req.Header.Set("Authorization", "token "+s.Token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("gitea: unexpected status %s for %s", resp.Status, endpoint)
+	}
+
+	var rels []giteaRelease
+	if err := json.NewDecoder(resp.Body).Decode(&rels); err != nil {
+		return nil, nil, err
+	}
+
+	out := make([]*SourceRelease, 0, len(rels))
+	for _, rel := range rels {
+		assets := make([]SourceAsset, 0, len(rel.Assets))
+		for _, a := range rel.Assets {
+			s.assetURLs[a.ID] = a.BrowserDownloadURL
+			assets = append(assets, SourceAsset{ID: a.ID, Name: a.Name, Size: a.Size, BrowserDownloadURL: a.BrowserDownloadURL})
+		}
+		out = append(out, &SourceRelease{
+			TagName:     rel.TagName,
+			Name:        rel.Name,
+			Body:        rel.Body,
+			URL:         rel.URL,
+			Draft:       rel.Draft,
+			Prerelease:  rel.Prerelease,
+			PublishedAt: rel.CreatedAt,
+			Assets:      assets,
+		})
+	}
+
+	nextPage := 0
+	if strings.Contains(resp.Header.Get("Link"), `rel="next"`) {
+		page := 1
+		if opts != nil && opts.Page > 0 {
+			page = opts.Page
+		}
+		nextPage = page + 1
+	}
+	return out, &ListResponse{NextPage: nextPage}, nil
+}
+
+// DownloadAsset implements ReleaseSource.
+func (s *GiteaSource) DownloadAsset(ctx context.Context, assetID int64) (io.ReadCloser, error) {
+	assetURL, ok := s.assetURLs[assetID]
+	if !ok {
+		return nil, fmt.Errorf("gitea: unknown asset id %d", assetID)
+	}
+	return httpDownload(ctx, s.httpClient, assetURL, s.Token, "Authorization")
+}
+
+// pageQuery renders opts as a "page=N&per_page=N" query string understood
+// by both the GitLab and Gitea REST APIs.
+func pageQuery(opts *ListOptions) string {
+	v := url.Values{}
+	if opts != nil {
+		if opts.Page > 0 {
+			v.Set("page", strconv.Itoa(opts.Page))
+		}
+		if opts.PerPage > 0 {
+			v.Set("per_page", strconv.Itoa(opts.PerPage))
+		}
+	}
+	return v.Encode()
+}
+
+func httpDownload(ctx context.Context, client *http.Client, rawURL, token, tokenHeader string) (io.ReadCloser, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	if token != "" {
+		if tokenHeader == "Authorization" {
+			req.Header.Set(tokenHeader, "token "+token)
+		} else {
+			req.Header.Set(tokenHeader, token)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s for %s", resp.Status, rawURL)
+	}
+	return resp.Body, nil
+}
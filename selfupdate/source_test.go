@@ -0,0 +1,115 @@
+package selfupdate
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPageQuery(t *testing.T) {
+	cases := []struct {
+		opts *ListOptions
+		want string
+	}{
+		{nil, ""},
+		{&ListOptions{}, ""},
+		{&ListOptions{Page: 2}, "page=2"},
+		{&ListOptions{Page: 2, PerPage: 50}, "page=2&per_page=50"},
+	}
+	for _, c := range cases {
+		if got := pageQuery(c.opts); got != c.want {
+			t.Errorf("pageQuery(%+v) = %q, want %q", c.opts, got, c.want)
+		}
+	}
+}
+
+func TestGitLabSourceListReleasesNextPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Next-Page", "3")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"tag_name":"v1.0.0","name":"v1.0.0"}]`))
+	}))
+	defer server.Close()
+
+	source := NewGitLabSource(server.URL, "")
+	rels, resp, err := source.ListReleases(context.Background(), "owner", "repo", &ListOptions{Page: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rels) != 1 || rels[0].TagName != "v1.0.0" {
+		t.Fatalf("unexpected releases: %+v", rels)
+	}
+	if resp.NextPage != 3 {
+		t.Errorf("expected NextPage 3 from X-Next-Page header, got %d", resp.NextPage)
+	}
+}
+
+func TestGitLabSourceListReleasesNoNextPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	source := NewGitLabSource(server.URL, "")
+	_, resp, err := source.ListReleases(context.Background(), "owner", "repo", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if resp.NextPage != 0 {
+		t.Errorf("expected NextPage 0 when X-Next-Page is absent, got %d", resp.NextPage)
+	}
+}
+
+func TestGiteaSourceListReleasesNextPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", `<https://example.com/?page=2>; rel="next"`)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"tag_name":"v1.0.0","id":0}]`))
+	}))
+	defer server.Close()
+
+	source := NewGiteaSource(server.URL, "")
+	rels, resp, err := source.ListReleases(context.Background(), "owner", "repo", &ListOptions{Page: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rels) != 1 || rels[0].TagName != "v1.0.0" {
+		t.Fatalf("unexpected releases: %+v", rels)
+	}
+	if resp.NextPage != 2 {
+		t.Errorf("expected NextPage 2 from a Link header with rel=\"next\", got %d", resp.NextPage)
+	}
+}
+
+func TestGiteaSourceListReleasesNoNextPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	source := NewGiteaSource(server.URL, "")
+	_, resp, err := source.ListReleases(context.Background(), "owner", "repo", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if resp.NextPage != 0 {
+		t.Errorf("expected NextPage 0 when no Link header names rel=\"next\", got %d", resp.NextPage)
+	}
+}
+
+func TestGitLabAndGiteaSourceNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if _, _, err := NewGitLabSource(server.URL, "").ListReleases(context.Background(), "owner", "repo", nil); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound from GitLabSource, got %v", err)
+	}
+	if _, _, err := NewGiteaSource(server.URL, "").ListReleases(context.Background(), "owner", "repo", nil); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound from GiteaSource, got %v", err)
+	}
+}
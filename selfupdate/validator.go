@@ -0,0 +1,115 @@
+package selfupdate
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Validator is implemented by types that can verify the integrity or
+// authenticity of a downloaded asset.
+type Validator interface {
+	// Validate validates data (the downloaded asset) against sig (the
+	// downloaded contents of its sibling validation asset).
+	Validate(data []byte, sig []byte) error
+
+	// Suffix returns the filename suffix appended to an asset's name to
+	// find its per-asset validation file, e.g. ".sha256" or ".asc".
+	Suffix() string
+}
+
+// ManifestValidator is implemented by a Validator that can locate the
+// data it needs to validate an asset inside a single combined manifest
+// shared by every asset in a release (e.g. "checksums.txt" or
+// "SHA256SUMS"), instead of requiring one sibling validation file per
+// asset. When up.validator implements this interface, detectVersion
+// prefers its manifest over the per-asset suffix lookup.
+type ManifestValidator interface {
+	Validator
+
+	// ManifestName returns the asset name of the combined manifest this
+	// validator expects to find in a release, e.g. "checksums.txt".
+	ManifestName() string
+
+	// ValidateFromManifest extracts the entry for assetName out of the
+	// manifest contents and validates data against it.
+	ValidateFromManifest(assetName string, data []byte, manifest []byte) error
+}
+
+// ChecksumManifestValidator validates an asset's SHA-256 checksum against
+// an entry in a combined checksum manifest in the common "sha256sum"
+// format: one "<hex digest>  <filename>" pair per line. GoReleaser's
+// default "checksums.txt" and the common "SHA256SUMS" naming both follow
+// this format.
+type ChecksumManifestValidator struct {
+	// Name is the manifest asset name to look for, e.g. "checksums.txt".
+	Name string
+}
+
+// ManifestName implements ManifestValidator.
+func (v *ChecksumManifestValidator) ManifestName() string {
+	return v.Name
+}
+
+// ValidateFromManifest implements ManifestValidator.
+func (v *ChecksumManifestValidator) ValidateFromManifest(assetName string, data []byte, manifest []byte) error {
+	digest, err := checksumForAsset(assetName, manifest)
+	if err != nil {
+		return err
+	}
+	return validateSHA256(data, digest)
+}
+
+// Validate implements Validator. It is never called when a manifest was
+// found, since ValidateFromManifest takes over at that point, but is
+// kept so ChecksumManifestValidator remains usable on its own for a
+// provider that has no manifest for a particular release.
+func (v *ChecksumManifestValidator) Validate(data []byte, sig []byte) error {
+	return validateSHA256(data, string(sig))
+}
+
+// Suffix implements Validator.
+func (v *ChecksumManifestValidator) Suffix() string {
+	return ".sha256"
+}
+
+// checksumForAsset scans a sha256sum-style manifest for the line naming
+// assetName and returns its hex digest.
+func checksumForAsset(assetName string, manifest []byte) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(manifest))
+	for scanner.Scan() {
+		var digest, name string
+		if _, err := fmt.Sscanf(scanner.Text(), "%s %s", &digest, &name); err != nil {
+			continue
+		}
+		// sha256sum separates text-mode entries with "  " and binary-mode
+		// entries with " *"; both are consumed as whitespace above, so
+		// only the leading '*' (if any) needs stripping here.
+		name = trimLeadingStar(name)
+		if name == assetName {
+			return digest, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no checksum entry found for %q in manifest", assetName)
+}
+
+func trimLeadingStar(name string) string {
+	if len(name) > 0 && name[0] == '*' {
+		return name[1:]
+	}
+	return name
+}
+
+func validateSHA256(data []byte, wantDigest string) error {
+	sum := sha256.Sum256(data)
+	got := hex.EncodeToString(sum[:])
+	if got != wantDigest {
+		return fmt.Errorf("sha256 checksum mismatch: got %q, want %q", got, wantDigest)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package selfupdate
+
+import "testing"
+
+func TestChecksumForAsset(t *testing.T) {
+	manifest := []byte(
+		"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa  myapp_linux_amd64.tar.gz\n" +
+			"bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb *myapp_darwin_amd64.tar.gz\n" +
+			"cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc  myapp_windows_amd64.zip\n",
+	)
+
+	digest, err := checksumForAsset("myapp_linux_amd64.tar.gz", manifest)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"; digest != want {
+		t.Errorf("expected digest %q, got %q", want, digest)
+	}
+
+	digest, err = checksumForAsset("myapp_darwin_amd64.tar.gz", manifest)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"; digest != want {
+		t.Errorf("expected binary-mode entry %q to be parsed without its leading '*', got %q", want, digest)
+	}
+}
+
+func TestChecksumForAssetNoEntry(t *testing.T) {
+	manifest := []byte("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa  myapp_linux_amd64.tar.gz\n")
+
+	if _, err := checksumForAsset("myapp_windows_amd64.zip", manifest); err == nil {
+		t.Fatal("expected an error for an asset with no manifest entry")
+	}
+}